@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+// app holds the global configuration shared by every subcommand: which
+// config file and journal to use, and the logging/search behavior flags.
+type app struct {
+	configFile  string
+	debug       bool
+	smartCase   bool
+	journal     string
+	nowOverride string
+
+	selectedJournal         string
+	selectedJournalLocation string
+
+	entryTemplate     *template.Template
+	searchHitTemplate *template.Template
+
+	resolvedNow *time.Time
+}
+
+// initLogging wires up zerolog based on the --debug flag.
+func (a *app) initLogging() {
+	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+
+	if a.debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+}
+
+// initConfig loads .journalrc.toml and resolves the selected journal's
+// location, honoring the --config and --journal overrides.
+func (a *app) initConfig() {
+	viper.SetConfigName(".journalrc")
+	viper.AddConfigPath("$HOME")
+
+	if a.configFile != "" {
+		viper.SetConfigFile(a.configFile)
+	}
+
+	if configErr := viper.ReadInConfig(); configErr != nil {
+		if _, ok := configErr.(viper.ConfigFileNotFoundError); ok {
+			log.Fatal().Msg("config not found")
+		} else {
+			log.Fatal().Err(configErr).Msg("config could not be read in")
+		}
+	}
+
+	a.selectedJournal = viper.GetString("journal.default")
+	if a.journal != "" {
+		a.selectedJournal = a.journal
+	}
+
+	a.selectedJournalLocation = viper.GetString(a.selectedJournal + ".location")
+	log.Debug().Str("name", a.selectedJournal).Str("location", a.selectedJournalLocation).Msg("default journal found")
+
+	if err := a.loadTemplates(viper.GetString("format.entry"), viper.GetString("format.search_hit")); err != nil {
+		log.Fatal().Err(err).Msg("could not load output templates")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/olebedev/when"
+	"github.com/olebedev/when/rules/common"
+	"github.com/olebedev/when/rules/en"
+	"github.com/rs/zerolog/log"
+)
+
+// now returns the current time, or the time pinned by --now/JOURNAL_NOW if
+// either was set. Every timestamp the app writes or resolves a relative date
+// against should go through this method instead of calling time.Now()
+// directly, so a pinned clock is reproducible across an entire invocation.
+func (a *app) now() time.Time {
+	if a.resolvedNow != nil {
+		return *a.resolvedNow
+	}
+
+	raw := a.nowOverride
+	if raw == "" {
+		raw = os.Getenv("JOURNAL_NOW")
+	}
+
+	t := time.Now()
+	if raw != "" {
+		parsed, err := parseNow(raw)
+		if err != nil {
+			log.Fatal().Err(err).Str("now", raw).Msg("could not parse --now")
+		}
+		t = parsed
+	}
+
+	a.resolvedNow = &t
+	return t
+}
+
+// parseNow parses the --now/JOURNAL_NOW value as RFC3339, falling back to
+// natural language (e.g. "yesterday at 9am") resolved against the real
+// clock, since there is no pinned time yet to resolve it against.
+func parseNow(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+
+	w := when.New(nil)
+	w.Add(en.All...)
+	w.Add(common.All...)
+
+	r, err := w.Parse(raw, time.Now())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not parse %q as a time", raw)
+	}
+
+	return r.Time, nil
+}
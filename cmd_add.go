@@ -0,0 +1,20 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newAddCmd builds the `journal add` subcommand.
+func newAddCmd(a *app) *cobra.Command {
+	var date string
+
+	cmd := &cobra.Command{
+		Use:   "add [text...]",
+		Short: "add a new entry to the selected journal",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.writeEntry(date, args)
+		},
+	}
+
+	cmd.Flags().StringVar(&date, "date", "", "add the entry under [DATE] instead of today")
+
+	return cmd
+}
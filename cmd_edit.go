@@ -0,0 +1,21 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newEditCmd builds the `journal edit` subcommand.
+func newEditCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit [date]",
+		Short: "edit entries added on [date] (default: today)",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var date string
+			if len(args) > 0 {
+				date = args[0]
+			}
+			return a.editEntry(date)
+		},
+	}
+
+	return cmd
+}
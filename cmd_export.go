@@ -0,0 +1,28 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// newExportCmd builds the `journal export` subcommand.
+func newExportCmd(a *app) *cobra.Command {
+	var since, until, on, format string
+
+	cmd := &cobra.Command{
+		Use:   "export [query]",
+		Short: "export matched entries as json, markdown, or an atom feed",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			criteria := a.searchCriteria(since, until, on, strings.Join(args, " "))
+			return a.exportEntries(format, criteria)
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "only export entries added after or on [DATE]")
+	cmd.Flags().StringVar(&until, "until", "", "only export entries added before or on [DATE]")
+	cmd.Flags().StringVar(&on, "on", "", "only export entries added on [DATE]")
+	cmd.Flags().StringVar(&format, "format", "json", "export format: json, md, or atom")
+
+	return cmd
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// newJournalsCmd builds the `journal journals` subcommand.
+func newJournalsCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "journals",
+		Short: "list the journals configured in .journalrc.toml",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.printJournals()
+		},
+	}
+
+	return cmd
+}
+
+// printJournals prints every journal defined in the config file, marking
+// the default one.
+func (a *app) printJournals() error {
+	defaultJournal := viper.GetString("journal.default")
+
+	settings := viper.AllSettings()
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		if name == "journal" || name == "format" {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		marker := " "
+		if name == defaultJournal {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\t%s\n", marker, name, viper.GetString(name+".location"))
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// newListCmd builds the `journal list` subcommand.
+func newListCmd(a *app) *cobra.Command {
+	var since, until, on string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "list entries in the selected journal, optionally filtered by date",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.printEntries(since, until, on, "")
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "view entries added after or on [DATE]")
+	cmd.Flags().StringVar(&until, "until", "", "view entries added before or on [DATE]")
+	cmd.Flags().StringVar(&on, "on", "", "view entries added on [DATE]")
+
+	return cmd
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/spf13/cobra"
+)
+
+// newLspCmd builds the `journal lsp` subcommand.
+func newLspCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lsp",
+		Short: "run a Language Server Protocol server over stdio for editor integration",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.runLSP()
+		},
+	}
+
+	return cmd
+}
+
+// runLSP speaks LSP over stdin/stdout until the client disconnects.
+func (a *app) runLSP() error {
+	s := newLSPServer(a)
+
+	stream := jsonrpc2.NewBufferedStream(stdio{os.Stdin, os.Stdout}, jsonrpc2.VSCodeObjectCodec{})
+	conn := jsonrpc2.NewConn(context.Background(), stream, jsonrpc2.HandlerWithError(s.handle))
+	<-conn.DisconnectNotify()
+
+	return nil
+}
+
+// stdio adapts stdin/stdout into the io.ReadWriteCloser jsonrpc2 expects.
+type stdio struct {
+	in  *os.File
+	out *os.File
+}
+
+func (s stdio) Read(p []byte) (int, error)  { return s.in.Read(p) }
+func (s stdio) Write(p []byte) (int, error) { return s.out.Write(p) }
+func (s stdio) Close() error {
+	_ = s.in.Close()
+	return s.out.Close()
+}
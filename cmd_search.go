@@ -0,0 +1,26 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// newSearchCmd builds the `journal search` subcommand.
+func newSearchCmd(a *app) *cobra.Command {
+	var since, until, on string
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "view entries matching a search query",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.printEntries(since, until, on, strings.Join(args, " "))
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "view entries added after or on [DATE]")
+	cmd.Flags().StringVar(&until, "until", "", "view entries added before or on [DATE]")
+	cmd.Flags().StringVar(&on, "on", "", "view entries added on [DATE]")
+
+	return cmd
+}
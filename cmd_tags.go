@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+// newTagsCmd builds the `journal tags` subcommand.
+func newTagsCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tags",
+		Short: "list all #tags used in the selected journal",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.printTags()
+		},
+	}
+
+	return cmd
+}
+
+// printTags walks the selected journal and prints every unique tag found
+// across its entries, sorted alphabetically.
+func (a *app) printTags() error {
+	rootPath, _ := homedir.Expand(a.selectedJournalLocation)
+
+	seen := make(map[string]bool)
+
+	err := filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info == nil || info.IsDir() {
+			return nil
+		}
+
+		content, rerr := ioutil.ReadFile(path)
+		if rerr != nil {
+			return nil
+		}
+
+		for _, e := range parseEntries(string(content)) {
+			for _, tag := range e.Tags {
+				seen[tag] = true
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	tags := make([]string, 0, len(seen))
+	for tag := range seen {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	for _, tag := range tags {
+		fmt.Println(tag)
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newTimeCmd builds the `journal time` subcommand family: start, stop, log,
+// and report.
+func newTimeCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "time",
+		Short: "track time spent and report on it",
+	}
+
+	cmd.AddCommand(
+		newTimeStartCmd(a),
+		newTimeStopCmd(a),
+		newTimeLogCmd(a),
+		newTimeReportCmd(a),
+	)
+
+	return cmd
+}
+
+func newTimeStartCmd(a *app) *cobra.Command {
+	var tag string
+
+	cmd := &cobra.Command{
+		Use:   "start [description...]",
+		Short: "start a timer",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.startTimer(tag, strings.Join(args, " "))
+		},
+	}
+
+	cmd.Flags().StringVar(&tag, "tag", "", "tag to record the time under")
+
+	return cmd
+}
+
+func newTimeStopCmd(a *app) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop",
+		Short: "stop the running timer and record its duration",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.stopTimer()
+		},
+	}
+
+	return cmd
+}
+
+func newTimeLogCmd(a *app) *cobra.Command {
+	var tag string
+
+	cmd := &cobra.Command{
+		Use:   "log <duration> [description...]",
+		Short: "record a duration without running a timer",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return a.logTime(args[0], tag, strings.Join(args[1:], " "))
+		},
+	}
+
+	cmd.Flags().StringVar(&tag, "tag", "", "tag to record the time under")
+
+	return cmd
+}
+
+func newTimeReportCmd(a *app) *cobra.Command {
+	var since, until, by string
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "total up logged time, grouped by tag, day, or journal",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			criteria := a.searchCriteria(since, until, "", "")
+
+			totals, err := a.timeReport(criteria, by)
+			if err != nil {
+				return err
+			}
+
+			var total time.Duration
+			for _, key := range sortedReportKeys(totals) {
+				d := totals[key]
+				total += d
+				fmt.Printf("%s\t%s\n", key, formatDuration(d))
+			}
+			fmt.Printf("total\t%s\n", formatDuration(total))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "only total entries added after or on [DATE]")
+	cmd.Flags().StringVar(&until, "until", "", "only total entries added before or on [DATE]")
+	cmd.Flags().StringVar(&by, "by", "tag", "group totals by tag, day, or journal")
+
+	return cmd
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kierdavis/dateparser"
+	"github.com/mitchellh/go-homedir"
+	"github.com/olebedev/when"
+	"github.com/olebedev/when/rules/common"
+	"github.com/olebedev/when/rules/en"
+	"github.com/rs/zerolog/log"
+)
+
+// writeEntry appends a new timestamped entry to the selected journal's daily
+// file, either with the text provided on the command line or, if none was
+// given, by opening the user's editor.
+func (a *app) writeEntry(date string, entryData []string) error {
+	t := a.now()
+	if date != "" {
+		t = robustParseTime(date, t)
+	}
+
+	// if the entry was provided on the cmd line, write it
+	if len(entryData) > 0 {
+		return a.appendEntryText(t, strings.Join(entryData, " "))
+	}
+
+	// if no entry data provided on the command line then pop open the editor
+
+	rootPath, _ := homedir.Expand(a.selectedJournalLocation)
+
+	// escape any spaces in folder names
+	dirPath := regexp.MustCompile(`(?m) `).ReplaceAllString(rootPath, "\\ ")
+	fPath := fmt.Sprintf("%s/%s.txt", rootPath, t.Format("2006-01-02"))
+
+	// write the timestamp to the file
+	timestamp := t.Format(entryTimestampFormat)
+	appendToFile(fPath, fmt.Sprintf("\n%s", timestamp))
+
+	// use the user's preferred EDITOR but default to vim
+	selectedEditor, ok := os.LookupEnv("EDITOR")
+	if !ok || selectedEditor == "vim" {
+		selectedEditor = "vim +'normal Ga'" // open vim with the cursor at the end of the file
+	}
+
+	editPath := fmt.Sprintf("%s %s/%s.txt", selectedEditor, dirPath, t.Format("2006-01-02"))
+
+	cmd := exec.Command("bash", "-c", editPath)
+	cmd.Stdout = os.Stdout
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// appendEntryText appends a new timestamped entry with the given body text
+// to t's daily file, e.g. for entries added on the command line or by the
+// time-tracking subcommands.
+func (a *app) appendEntryText(t time.Time, text string) error {
+	rootPath, _ := homedir.Expand(a.selectedJournalLocation)
+	fPath := fmt.Sprintf("%s/%s.txt", rootPath, t.Format("2006-01-02"))
+
+	appendToFile(fPath, fmt.Sprintf("\n%s", t.Format(entryTimestampFormat)))
+	appendToFile(fPath, "- "+text)
+
+	return nil
+}
+
+// editEntry opens the daily file for the given date (or today, if date is
+// empty) in the user's editor.
+func (a *app) editEntry(date string) error {
+	// use the user's preferred EDITOR but default to vim
+	selectedEditor, ok := os.LookupEnv("EDITOR")
+	if !ok {
+		selectedEditor = "vim"
+	}
+
+	// open the editor
+	editor, err := exec.LookPath(selectedEditor)
+	if err != nil {
+		return fmt.Errorf("could not find an appropriate editor: %w", err)
+	}
+
+	t := robustParseTime(date, a.now())
+
+	// escape any spaces in folder names
+	fPath := regexp.MustCompile(`(?m) `).ReplaceAllString(a.selectedJournalLocation, "\\ ")
+
+	cmd := exec.Command("bash", "-c", fmt.Sprintf("%s %s/%s.txt", editor, fPath, t.Format("2006-01-02")))
+	cmd.Stdout = os.Stdout
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// printEntries builds a SearchCriteria from the date filters and query, then
+// prints every entry across the selected journal that matches it.
+func (a *app) printEntries(since, until, on, query string) error {
+	rootPath, _ := homedir.Expand(a.selectedJournalLocation)
+
+	criteria := a.searchCriteria(since, until, on, query)
+
+	log.Debug().Str("path", rootPath).Interface("criteria", criteria).Msg("searching for entries to print")
+
+	return filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if info != nil && !info.IsDir() {
+			a.printFile(path, criteria)
+		}
+		return nil
+	})
+}
+
+// searchCriteria builds a SearchCriteria from the date filters and query
+// string a subcommand was invoked with.
+func (a *app) searchCriteria(since, until, on, query string) *SearchCriteria {
+	criteria := ParseQuery(query)
+
+	if since != "" {
+		criteria.Since = robustParseTime(since, a.now())
+	}
+	if until != "" {
+		criteria.Until = robustParseTime(until, a.now())
+	}
+	if on != "" {
+		criteria.On = robustParseTime(on, a.now())
+	}
+	if !a.smartCase {
+		criteria.CaseSensitive = triTrue
+	}
+
+	return criteria
+}
+
+// printFile prints all entries in a file that match the given criteria,
+// rendered through the configured entry/search_hit templates
+func (a *app) printFile(fileName string, criteria *SearchCriteria) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	content, _ := ioutil.ReadAll(file)
+	text := string(content)
+
+	for _, loc := range entryRE.FindAllStringSubmatchIndex(text, -1) {
+		e, ok := entryFromMatch(text, loc)
+		if !ok || !criteria.Matches(e) {
+			continue
+		}
+
+		var (
+			out string
+			err error
+		)
+		if criteria.isSearch() {
+			lineNo := strings.Count(text[:loc[0]], "\n") + 1
+			out, err = a.renderSearchHit(fileName, lineNo, criteria.firstTerm(), e)
+		} else {
+			out, err = a.renderEntry(a.selectedJournal, e)
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("failed to render entry")
+			continue
+		}
+
+		fmt.Println(out)
+	}
+}
+
+// helper function to write a string to a file
+func appendToFile(file, data string) {
+	f, err := os.OpenFile(file,
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		log.Error().Err(err).Msg("error opening file")
+	}
+
+	defer f.Close()
+	_, _ = f.WriteString(data + "\n")
+}
+
+// attempt parsing a datetime string using a couple of libraries, resolving
+// relative dates (e.g. "yesterday") against ref rather than the real clock
+func robustParseTime(rawTime string, ref time.Time) time.Time {
+	// first try parsing with https://github.com/kierdavis/dateparser
+	parser := &dateparser.Parser{Default: ref}
+	t, err := parser.Parse(rawTime)
+	if err == nil {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+
+	// if that failed then try with https://github.com/olebedev/when
+	w := when.New(nil)
+	w.Add(en.All...)
+	w.Add(common.All...)
+
+	r, err := w.Parse(rawTime, ref)
+	if err == nil {
+		t = r.Time.UTC()
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+
+	// if both failed, return a nil time
+	return time.Time{}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/feeds"
+	"github.com/mitchellh/go-homedir"
+)
+
+// exportRecord is the JSON shape written by `journal export --format json`,
+// one object per matched entry.
+type exportRecord struct {
+	Journal   string   `json:"journal"`
+	Date      string   `json:"date"`
+	Timestamp string   `json:"timestamp"`
+	Tags      []string `json:"tags,omitempty"`
+	Body      string   `json:"body"`
+}
+
+// exportEntries walks the selected journal, collects every entry matching
+// criteria, sorts them chronologically, and writes them to stdout in the
+// requested format.
+func (a *app) exportEntries(format string, criteria *SearchCriteria) error {
+	rootPath, err := homedir.Expand(a.selectedJournalLocation)
+	if err != nil {
+		return err
+	}
+
+	var entries []Entry
+	err = filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info == nil || info.IsDir() {
+			return nil
+		}
+
+		content, rerr := ioutil.ReadFile(path)
+		if rerr != nil {
+			return nil
+		}
+
+		for _, e := range parseEntries(string(content)) {
+			if criteria.Matches(e) {
+				entries = append(entries, e)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+
+	switch format {
+	case "json":
+		return a.exportJSON(entries)
+	case "md", "markdown":
+		return a.exportMarkdown(entries)
+	case "atom":
+		return a.exportAtom(entries)
+	default:
+		return fmt.Errorf("unsupported export format %q (want json, md, or atom)", format)
+	}
+}
+
+// exportJSON streams one JSON object per entry, suitable for jq pipelines or
+// piping straight into a backup file.
+func (a *app) exportJSON(entries []Entry) error {
+	enc := json.NewEncoder(os.Stdout)
+
+	for _, e := range entries {
+		record := exportRecord{
+			Journal:   a.selectedJournal,
+			Date:      e.Timestamp.Format("2006-01-02"),
+			Timestamp: e.Timestamp.Format(entryTimestampFormat),
+			Tags:      e.Tags,
+			Body:      e.Body,
+		}
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportMarkdown renders entries as a chronological document, one "##" day
+// heading per date with each entry's body as a bullet underneath.
+func (a *app) exportMarkdown(entries []Entry) error {
+	var lastDay string
+
+	for _, e := range entries {
+		day := e.Timestamp.Format("2006-01-02")
+		if day != lastDay {
+			if lastDay != "" {
+				fmt.Println()
+			}
+			fmt.Printf("## %s\n\n", day)
+			lastDay = day
+		}
+
+		body := strings.ReplaceAll(e.Body, "\n", "\n  ")
+		fmt.Printf("- %s %s\n", e.Timestamp.Format("15:04:05"), body)
+	}
+
+	return nil
+}
+
+// exportAtom renders entries as an Atom feed that a static-site generator or
+// feed reader can consume.
+func (a *app) exportAtom(entries []Entry) error {
+	rootPath, _ := homedir.Expand(a.selectedJournalLocation)
+
+	feed := &feeds.Feed{
+		Title:       fmt.Sprintf("journal: %s", a.selectedJournal),
+		Link:        &feeds.Link{Href: "file://" + rootPath},
+		Description: fmt.Sprintf("exported entries from the %q journal", a.selectedJournal),
+		Created:     a.now(),
+	}
+
+	for _, e := range entries {
+		feed.Items = append(feed.Items, &feeds.Item{
+			Title:       e.Timestamp.Format(entryTimestampFormat),
+			Link:        &feeds.Link{Href: fmt.Sprintf("file://%s/%s.txt", rootPath, e.Timestamp.Format("2006-01-02"))},
+			Description: e.Body,
+			Created:     e.Timestamp,
+		})
+	}
+
+	return feed.WriteAtom(os.Stdout)
+}
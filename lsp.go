@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/rs/zerolog/log"
+	lsp "github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/jsonrpc2"
+)
+
+// seeDateRE matches a "see 2024-05-01" style date reference on a line, used
+// by textDocument/definition to jump to that day's file.
+var seeDateRE = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+// lspServer implements the handful of LSP methods needed for editors to
+// complete #tags, hover an entry's timestamp, and jump to a referenced day.
+// It reuses the same entry parser and tag extractor as the CLI and tags
+// subcommand, so the LSP and CLI always see identical data.
+type lspServer struct {
+	app      *app
+	rootPath string
+
+	mu   sync.RWMutex
+	tags map[string]bool
+}
+
+func newLSPServer(a *app) *lspServer {
+	rootPath, _ := homedir.Expand(a.selectedJournalLocation)
+	return &lspServer{app: a, rootPath: rootPath}
+}
+
+// handle dispatches a single LSP request or notification.
+func (s *lspServer) handle(ctx context.Context, conn *jsonrpc2.Conn, req *jsonrpc2.Request) (interface{}, error) {
+	switch req.Method {
+	case "initialize":
+		var params lsp.InitializeParams
+		if req.Params != nil {
+			if err := json.Unmarshal(*req.Params, &params); err != nil {
+				return nil, err
+			}
+		}
+		if root := uriToPath(params.Root()); root != "" {
+			s.rootPath = root
+		}
+		if err := s.reindex(); err != nil {
+			log.Error().Err(err).Msg("failed to build initial tag index")
+		}
+
+		return lsp.InitializeResult{
+			Capabilities: lsp.ServerCapabilities{
+				HoverProvider:      true,
+				DefinitionProvider: true,
+				CompletionProvider: &lsp.CompletionOptions{TriggerCharacters: []string{"#"}},
+				ExecuteCommandProvider: &lsp.ExecuteCommandOptions{
+					Commands: []string{"journal.new", "journal.reindex"},
+				},
+			},
+		}, nil
+
+	case "initialized", "shutdown":
+		return nil, nil
+
+	case "exit":
+		os.Exit(0)
+		return nil, nil
+
+	case "textDocument/completion":
+		var params lsp.CompletionParams
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.completion(params)
+
+	case "textDocument/hover":
+		var params lsp.TextDocumentPositionParams
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.hover(params)
+
+	case "textDocument/definition":
+		var params lsp.TextDocumentPositionParams
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.definition(params)
+
+	case "textDocument/didSave":
+		var params lsp.DidSaveTextDocumentParams
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return nil, err
+		}
+		if err := s.reindex(); err != nil {
+			log.Error().Err(err).Msg("failed to reindex after save")
+		}
+		return nil, nil
+
+	case "workspace/executeCommand":
+		var params lsp.ExecuteCommandParams
+		if err := json.Unmarshal(*req.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.executeCommand(params)
+
+	default:
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeMethodNotFound, Message: fmt.Sprintf("method not supported: %s", req.Method)}
+	}
+}
+
+// completion offers #tag completions for the prefix under the cursor.
+func (s *lspServer) completion(params lsp.CompletionParams) (*lsp.CompletionList, error) {
+	line, err := readLine(uriToPath(params.TextDocument.URI), params.Position.Line)
+	if err != nil {
+		return &lsp.CompletionList{}, nil
+	}
+
+	prefix, ok := tagPrefix(line, params.Position.Character)
+	if !ok {
+		return &lsp.CompletionList{}, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var items []lsp.CompletionItem
+	for tag := range s.tags {
+		if strings.HasPrefix(strings.ToLower(tag), strings.ToLower(prefix)) {
+			items = append(items, lsp.CompletionItem{Label: "#" + tag, Kind: 1, InsertText: tag})
+		}
+	}
+
+	return &lsp.CompletionList{Items: items}, nil
+}
+
+// hover shows the timestamp block of the entry under the cursor.
+func (s *lspServer) hover(params lsp.TextDocumentPositionParams) (*lsp.Hover, error) {
+	path := uriToPath(params.TextDocument.URI)
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return &lsp.Hover{}, nil
+	}
+
+	e, ok := entryAtLine(string(content), params.Position.Line)
+	if !ok {
+		return &lsp.Hover{}, nil
+	}
+
+	return &lsp.Hover{Contents: []lsp.MarkedString{lsp.RawMarkedString(e.Timestamp.Format(entryTimestampFormat))}}, nil
+}
+
+// definition jumps from a "see 2024-05-01" style reference to that day's file.
+func (s *lspServer) definition(params lsp.TextDocumentPositionParams) (*lsp.Location, error) {
+	line, err := readLine(uriToPath(params.TextDocument.URI), params.Position.Line)
+	if err != nil {
+		return nil, nil
+	}
+
+	date := seeDateRE.FindString(line)
+	if date == "" {
+		return nil, nil
+	}
+
+	path := filepath.Join(s.rootPath, date+".txt")
+	return &lsp.Location{URI: lsp.DocumentURI("file://" + path)}, nil
+}
+
+// executeCommand implements journal.new (start today's entry) and
+// journal.reindex (rebuild the tag index).
+func (s *lspServer) executeCommand(params lsp.ExecuteCommandParams) (interface{}, error) {
+	switch params.Command {
+	case "journal.new":
+		return s.newEntry()
+	case "journal.reindex":
+		return nil, s.reindex()
+	default:
+		return nil, &jsonrpc2.Error{Code: jsonrpc2.CodeInvalidParams, Message: fmt.Sprintf("unknown command: %s", params.Command)}
+	}
+}
+
+// newEntry appends a fresh timestamp block to today's file and returns its
+// URI so the client can open it.
+func (s *lspServer) newEntry() (lsp.DocumentURI, error) {
+	t := s.app.now()
+	fPath := filepath.Join(s.rootPath, t.Format("2006-01-02")+".txt")
+	appendToFile(fPath, fmt.Sprintf("\n%s", t.Format(entryTimestampFormat)))
+	return lsp.DocumentURI("file://" + fPath), nil
+}
+
+// reindex rebuilds the #tag index by walking the journal directory.
+func (s *lspServer) reindex() error {
+	seen := make(map[string]bool)
+
+	err := filepath.Walk(s.rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info == nil || info.IsDir() {
+			return nil
+		}
+
+		content, rerr := ioutil.ReadFile(path)
+		if rerr != nil {
+			return nil
+		}
+
+		for _, e := range parseEntries(string(content)) {
+			for _, tag := range e.Tags {
+				seen[tag] = true
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.tags = seen
+	s.mu.Unlock()
+
+	return nil
+}
+
+// uriToPath converts a file:// URI into a filesystem path.
+func uriToPath(uri lsp.DocumentURI) string {
+	return strings.TrimPrefix(string(uri), "file://")
+}
+
+// readLine returns the given 0-based line of the file at path.
+func readLine(path string, line int) (string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	if line < 0 || line >= len(lines) {
+		return "", fmt.Errorf("line %d out of range", line)
+	}
+
+	return lines[line], nil
+}
+
+// tagPrefix returns the partial #tag text immediately before character on
+// line, if the cursor is positioned inside one.
+func tagPrefix(line string, character int) (string, bool) {
+	if character > len(line) {
+		character = len(line)
+	}
+	head := line[:character]
+
+	idx := strings.LastIndexByte(head, '#')
+	if idx == -1 {
+		return "", false
+	}
+
+	prefix := head[idx+1:]
+	if strings.ContainsAny(prefix, " \t") {
+		return "", false
+	}
+
+	return prefix, true
+}
+
+// entryAtLine finds the entry in content whose timestamp-and-body block
+// spans the given 0-based line number.
+func entryAtLine(content string, line int) (Entry, bool) {
+	for _, loc := range entryRE.FindAllStringSubmatchIndex(content, -1) {
+		startLine := strings.Count(content[:loc[0]], "\n")
+		endLine := strings.Count(content[:loc[1]], "\n")
+		if line < startLine || line > endLine {
+			continue
+		}
+
+		return entryFromMatch(content, loc)
+	}
+
+	return Entry{}, false
+}
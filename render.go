@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// defaultEntryTemplate and defaultSearchHitTemplate reproduce the tool's
+// historical rendering: a highlighted timestamp header followed by the
+// entry body, with a trailing blank line so multiple entries keep the
+// blank-line separation they had when a day's file was printed verbatim.
+const (
+	defaultEntryTemplate     = `{{ style "magenta" .Timestamp }}` + "\n" + `{{ .Body }}` + "\n"
+	defaultSearchHitTemplate = defaultEntryTemplate
+)
+
+// ansiColors maps the color names available to the "style" template helper
+// to their ANSI SGR codes. magenta is 95 (bright), matching the color the
+// tool has always used for timestamps.
+var ansiColors = map[string]int{
+	"black":   30,
+	"red":     31,
+	"green":   32,
+	"yellow":  33,
+	"blue":    34,
+	"magenta": 95,
+	"cyan":    36,
+	"white":   37,
+	"bold":    1,
+}
+
+// EntryView is the data made available to the "entry" format template.
+type EntryView struct {
+	Time      time.Time
+	Timestamp string
+	Body      string
+	Tags      []string
+	Journal   string
+}
+
+// SearchHitView is the data made available to the "search_hit" format
+// template; it extends EntryView with the location of the match.
+type SearchHitView struct {
+	EntryView
+	Path   string
+	LineNo int
+	Match  string
+}
+
+// templateFuncs returns the helpers available to both format templates.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"style":     styleText,
+		"highlight": highlightText,
+		"date":      dateText,
+		"tag":       tagText,
+	}
+}
+
+// styleText wraps s in the ANSI escape codes for the named color. Unknown
+// color names are returned unstyled.
+func styleText(name, s string) string {
+	code, ok := ansiColors[name]
+	if !ok {
+		return s
+	}
+	return fmt.Sprintf("\x1b[%dm%s\x1b[0m", code, s)
+}
+
+// highlightText bolds every case-insensitive occurrence of match within s.
+// If match is empty, s is returned unchanged.
+func highlightText(match, s string) string {
+	if match == "" {
+		return s
+	}
+
+	re, err := regexp.Compile(`(?i)` + regexp.QuoteMeta(match))
+	if err != nil {
+		return s
+	}
+
+	return re.ReplaceAllStringFunc(s, func(m string) string {
+		return styleText("bold", m)
+	})
+}
+
+// dateText formats t using the given reference layout, e.g. "Jan 2".
+func dateText(layout string, t time.Time) string {
+	return t.Format(layout)
+}
+
+// tagText formats a tag for display, e.g. in a custom template that lists
+// an entry's tags.
+func tagText(t string) string {
+	return styleText("cyan", "#"+t)
+}
+
+// loadTemplates parses the "format.entry" and "format.search_hit" templates
+// from the config, falling back to defaults that reproduce the historical
+// output when either is unset.
+func (a *app) loadTemplates(entrySrc, searchHitSrc string) error {
+	if entrySrc == "" {
+		entrySrc = defaultEntryTemplate
+	}
+	if searchHitSrc == "" {
+		searchHitSrc = defaultSearchHitTemplate
+	}
+
+	entryTmpl, err := template.New("entry").Funcs(templateFuncs()).Parse(entrySrc)
+	if err != nil {
+		return fmt.Errorf("parsing format.entry template: %w", err)
+	}
+
+	searchHitTmpl, err := template.New("search_hit").Funcs(templateFuncs()).Parse(searchHitSrc)
+	if err != nil {
+		return fmt.Errorf("parsing format.search_hit template: %w", err)
+	}
+
+	a.entryTemplate = entryTmpl
+	a.searchHitTemplate = searchHitTmpl
+
+	return nil
+}
+
+// renderEntry renders e using the configured "entry" template.
+func (a *app) renderEntry(journal string, e Entry) (string, error) {
+	view := EntryView{
+		Time:      e.Timestamp,
+		Timestamp: e.Timestamp.Format(entryTimestampFormat),
+		Body:      e.Body,
+		Tags:      e.Tags,
+		Journal:   journal,
+	}
+
+	var buf strings.Builder
+	if err := a.entryTemplate.Execute(&buf, view); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// renderSearchHit renders e using the configured "search_hit" template.
+func (a *app) renderSearchHit(path string, lineNo int, match string, e Entry) (string, error) {
+	view := SearchHitView{
+		EntryView: EntryView{
+			Time:      e.Timestamp,
+			Timestamp: e.Timestamp.Format(entryTimestampFormat),
+			Body:      e.Body,
+			Tags:      e.Tags,
+		},
+		Path:   path,
+		LineNo: lineNo,
+		Match:  match,
+	}
+
+	var buf strings.Builder
+	if err := a.searchHitTemplate.Execute(&buf, view); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// entryTimestampFormat is the layout used for the timestamp header written
+// above every entry (see writeEntry).
+const entryTimestampFormat = "Mon 01/02/06 15:04:05"
+
+var (
+	entryRE = regexp.MustCompile(`(?ms)^(\w+ \d+/\d+/\d+ \d+:\d+:\d+)\n(.*?)^$`)
+	tagRE   = regexp.MustCompile(`#(\w+)`)
+)
+
+// Entry is a single journal entry parsed out of a daily file.
+type Entry struct {
+	Timestamp time.Time
+	Body      string
+	Tags      []string
+}
+
+// triState represents an optional boolean that can also be left unset so
+// callers can tell "not specified" apart from "explicitly false".
+type triState int
+
+const (
+	triUnset triState = iota
+	triTrue
+	triFalse
+)
+
+// searchScope controls which part of an entry a term is matched against.
+type searchScope int
+
+const (
+	// ScopeBody matches terms against the entry body only. This is the
+	// default and matches the tool's historical behavior.
+	ScopeBody searchScope = iota
+	// ScopeTimestamp matches terms against the entry's rendered timestamp.
+	ScopeTimestamp
+	// ScopeAny matches terms against the timestamp and the body.
+	ScopeAny
+)
+
+// SearchCriteria describes a filter over journal entries. It is the single
+// evaluator used by both printEntries and export: a caller builds one from
+// flags and/or a query string, then calls Matches against each parsed Entry.
+type SearchCriteria struct {
+	Since time.Time
+	Until time.Time
+	On    time.Time
+
+	Terms    []string // AND'd
+	AnyTerms []string // OR'd
+	Not      []string // none of these may match
+	Tags     []string // #tag filters, all required
+	Journals []string // restrict to these journals, empty means all
+
+	CaseSensitive triState
+	Scope         searchScope
+}
+
+// ParseQuery parses the small query DSL accepted by -s/--search into a
+// SearchCriteria. It supports quoted phrases ("foo bar"), tag:foo filters,
+// -word negation, and word|word OR groups. Anything left over is AND'd
+// together as a required term.
+func ParseQuery(raw string) *SearchCriteria {
+	c := &SearchCriteria{}
+
+	for _, tok := range tokenizeQuery(raw) {
+		switch {
+		case tok == "":
+			continue
+		case strings.HasPrefix(tok, "tag:"):
+			c.Tags = append(c.Tags, strings.TrimPrefix(tok, "tag:"))
+		case strings.HasPrefix(tok, "-"):
+			c.Not = append(c.Not, strings.TrimPrefix(tok, "-"))
+		case strings.Contains(tok, "|"):
+			c.AnyTerms = append(c.AnyTerms, strings.Split(tok, "|")...)
+		default:
+			c.Terms = append(c.Terms, tok)
+		}
+	}
+
+	return c
+}
+
+// tokenizeQuery splits a query string on whitespace, treating double-quoted
+// spans as a single token.
+func tokenizeQuery(raw string) []string {
+	var tokens []string
+	var cur strings.Builder
+	inQuotes := false
+
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case unicode.IsSpace(r) && !inQuotes:
+			if cur.Len() > 0 {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+
+	return tokens
+}
+
+// Matches reports whether the entry satisfies the criteria.
+func (c *SearchCriteria) Matches(e Entry) bool {
+	if !c.Since.IsZero() && dateOnly(e.Timestamp).Before(c.Since) {
+		return false
+	}
+	if !c.Until.IsZero() && dateOnly(e.Timestamp).After(c.Until) {
+		return false
+	}
+	if !c.On.IsZero() && !dateOnly(e.Timestamp).Equal(c.On) {
+		return false
+	}
+	if len(c.Tags) > 0 && !containsAllTags(e.Tags, c.Tags) {
+		return false
+	}
+
+	haystack := c.searchText(e)
+
+	for _, term := range c.Not {
+		if c.textMatches(haystack, term) {
+			return false
+		}
+	}
+	for _, term := range c.Terms {
+		if !c.textMatches(haystack, term) {
+			return false
+		}
+	}
+	if len(c.AnyTerms) > 0 {
+		matched := false
+		for _, term := range c.AnyTerms {
+			if c.textMatches(haystack, term) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isSearch reports whether the criteria carries a text query, as opposed to
+// being a plain date-bounded listing.
+func (c *SearchCriteria) isSearch() bool {
+	return len(c.Terms) > 0 || len(c.AnyTerms) > 0 || len(c.Not) > 0
+}
+
+// firstTerm returns a representative query term for highlighting purposes.
+func (c *SearchCriteria) firstTerm() string {
+	if len(c.Terms) > 0 {
+		return c.Terms[0]
+	}
+	if len(c.AnyTerms) > 0 {
+		return c.AnyTerms[0]
+	}
+	return ""
+}
+
+// searchText returns the text a term is matched against, per c.Scope.
+func (c *SearchCriteria) searchText(e Entry) string {
+	switch c.Scope {
+	case ScopeTimestamp:
+		return e.Timestamp.Format(entryTimestampFormat)
+	case ScopeAny:
+		return e.Timestamp.Format(entryTimestampFormat) + "\n" + e.Body
+	default:
+		return e.Body
+	}
+}
+
+// textMatches performs a vim-style smartcase match unless CaseSensitive
+// explicitly overrides it: case insensitive if the term is lowercase, case
+// sensitive if it starts with a capital letter.
+func (c *SearchCriteria) textMatches(haystack, term string) bool {
+	caseSensitive := len(term) > 0 && !unicode.IsLower(rune(term[0]))
+
+	switch c.CaseSensitive {
+	case triTrue:
+		caseSensitive = true
+	case triFalse:
+		caseSensitive = false
+	}
+
+	if caseSensitive {
+		return strings.Contains(haystack, term)
+	}
+	return strings.Contains(strings.ToLower(haystack), strings.ToLower(term))
+}
+
+// parseEntries splits the contents of a daily file into individual entries.
+func parseEntries(content string) []Entry {
+	var entries []Entry
+
+	for _, loc := range entryRE.FindAllStringSubmatchIndex(content, -1) {
+		if e, ok := entryFromMatch(content, loc); ok {
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
+
+// entryFromMatch builds an Entry from one entryRE submatch index set, as
+// returned by FindAllStringSubmatchIndex: loc[2:4] is the timestamp group,
+// loc[4:6] is the body group.
+func entryFromMatch(content string, loc []int) (Entry, bool) {
+	ts, err := time.Parse(entryTimestampFormat, content[loc[2]:loc[3]])
+	if err != nil {
+		return Entry{}, false
+	}
+
+	body := strings.TrimRight(content[loc[4]:loc[5]], "\n")
+	return Entry{Timestamp: ts, Body: body, Tags: extractTags(body)}, true
+}
+
+// extractTags pulls out the unique #tag tokens found in an entry's body.
+func extractTags(body string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+
+	for _, m := range tagRE.FindAllStringSubmatch(body, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			tags = append(tags, m[1])
+		}
+	}
+
+	return tags
+}
+
+func containsAllTags(tags, required []string) bool {
+	for _, r := range required {
+		if !containsTag(tags, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// dateOnly zeroes out the time-of-day component of t, leaving just the date.
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+// TestParseEntriesBody guards against a regression where writeEntry's output
+// format and entryRE's body terminator disagreed: if a blank line is ever
+// reintroduced between the timestamp header and the body (e.g. "\n%s\n\n"),
+// entryRE's "^$" terminator matches that blank line first and every entry's
+// Body comes back empty.
+func TestParseEntriesBody(t *testing.T) {
+	content := "\nMon 01/02/06 15:04:05\n- went for a walk #exercise\n"
+
+	entries := parseEntries(content)
+	if len(entries) != 1 {
+		t.Fatalf("parseEntries() returned %d entries, want 1", len(entries))
+	}
+
+	if got, want := entries[0].Body, "- went for a walk #exercise"; got != want {
+		t.Errorf("Body = %q, want %q", got, want)
+	}
+
+	if got, want := entries[0].Tags, []string{"exercise"}; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("Tags = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+// timeBulletRE matches a time-tracking bullet appended by the time
+// subcommands, e.g. "- [45m #work] wrote parser".
+var timeBulletRE = regexp.MustCompile(`^-\s\[(\S+)(?:\s+#(\w+))?\]\s*(.*)$`)
+
+// timerState is the in-progress timer persisted by `journal time start` and
+// consumed by `journal time stop`.
+type timerState struct {
+	Tag         string    `json:"tag,omitempty"`
+	Description string    `json:"description,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+}
+
+// timerStatePath returns the path of the running timer's state file. It
+// lives alongside the selected journal's daily files so each journal can
+// track its own timer.
+func (a *app) timerStatePath() (string, error) {
+	rootPath, err := homedir.Expand(a.selectedJournalLocation)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(rootPath, ".timer.json"), nil
+}
+
+// startTimer begins a new timer, failing if one is already running.
+func (a *app) startTimer(tag, description string) error {
+	path, err := a.timerStatePath()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); err == nil {
+		existing, rerr := readTimerState(path)
+		if rerr == nil {
+			return fmt.Errorf("a timer is already running (started %s), run `journal time stop` first", existing.StartedAt.Format(entryTimestampFormat))
+		}
+		return fmt.Errorf("a timer is already running, run `journal time stop` first")
+	}
+
+	state := timerState{Tag: tag, Description: description, StartedAt: a.now()}
+	return writeTimerState(path, state)
+}
+
+// stopTimer ends the running timer and appends a duration bullet to today's
+// entry recording how long it ran.
+func (a *app) stopTimer() error {
+	path, err := a.timerStatePath()
+	if err != nil {
+		return err
+	}
+
+	state, err := readTimerState(path)
+	if err != nil {
+		return fmt.Errorf("no timer is running")
+	}
+
+	elapsed := a.now().Sub(state.StartedAt)
+
+	if err := a.appendEntryText(a.now(), timeBullet(formatDuration(elapsed), state.Tag, state.Description)); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// logTime appends a duration bullet directly, without a running timer.
+func (a *app) logTime(rawDuration, tag, description string) error {
+	d, err := time.ParseDuration(rawDuration)
+	if err != nil {
+		return fmt.Errorf("could not parse duration %q: %w", rawDuration, err)
+	}
+
+	return a.appendEntryText(a.now(), timeBullet(formatDuration(d), tag, description))
+}
+
+func readTimerState(path string) (timerState, error) {
+	var state timerState
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return state, err
+	}
+
+	err = json.Unmarshal(data, &state)
+	return state, err
+}
+
+func writeTimerState(path string, state timerState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0o644)
+}
+
+// timeBullet builds the "[duration #tag] description" bullet text appended
+// to the daily file by start/stop/log.
+func timeBullet(duration, tag, description string) string {
+	label := "[" + duration
+	if tag != "" {
+		label += " #" + tag
+	}
+	label += "]"
+
+	if description != "" {
+		return label + " " + description
+	}
+	return label
+}
+
+// timeReport walks entries matching criteria, sums the duration bullets
+// found in their bodies, and groups the totals by the requested dimension
+// ("tag", "day", or "journal").
+func (a *app) timeReport(criteria *SearchCriteria, by string) (map[string]time.Duration, error) {
+	rootPath, err := homedir.Expand(a.selectedJournalLocation)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]time.Duration)
+
+	err = filepath.Walk(rootPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info == nil || info.IsDir() {
+			return nil
+		}
+
+		content, rerr := ioutil.ReadFile(path)
+		if rerr != nil {
+			return nil
+		}
+
+		for _, e := range parseEntries(string(content)) {
+			if !criteria.Matches(e) {
+				continue
+			}
+
+			for _, line := range strings.Split(e.Body, "\n") {
+				m := timeBulletRE.FindStringSubmatch(line)
+				if m == nil {
+					continue
+				}
+
+				d, derr := time.ParseDuration(m[1])
+				if derr != nil {
+					continue
+				}
+
+				totals[a.reportKey(by, e, m[2])] += d
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+// reportKey returns the grouping key for an entry's time bullet, per the
+// --by dimension.
+func (a *app) reportKey(by string, e Entry, tag string) string {
+	switch by {
+	case "day":
+		return e.Timestamp.Format("2006-01-02")
+	case "journal":
+		return a.selectedJournal
+	default:
+		if tag == "" {
+			return "untagged"
+		}
+		return tag
+	}
+}
+
+// formatDuration renders d to the minute, e.g. "1h30m" or "45m".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+
+	h := d / time.Hour
+	m := (d - h*time.Hour) / time.Minute
+
+	switch {
+	case h > 0 && m > 0:
+		return fmt.Sprintf("%dh%dm", h, m)
+	case h > 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dm", m)
+	}
+}
+
+// sortedReportKeys returns totals' keys in alphabetical order, for stable
+// report output.
+func sortedReportKeys(totals map[string]time.Duration) []string {
+	keys := make([]string, 0, len(totals))
+	for k := range totals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}